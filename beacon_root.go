@@ -0,0 +1,60 @@
+package execution
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BeaconRootProvider supplies the parentBeaconBlockRoot to include in the
+// next payload once Cancun is active. Implementations are expected to be
+// populated out-of-band, e.g. by a client following the consensus layer's
+// beacon chain, and to be safe for concurrent use.
+type BeaconRootProvider interface {
+	// ParentBeaconBlockRoot returns the parent beacon block root to use for
+	// the payload built at the given height.
+	ParentBeaconBlockRoot(height uint64) (common.Hash, error)
+}
+
+// SetBeaconRootProvider configures the source of parentBeaconBlockRoot to
+// include in payloads once Cancun is active. If unset, payloads carry the
+// zero hash, which a real consensus layer will reject.
+func (c *EngineAPIExecutionClient) SetBeaconRootProvider(p BeaconRootProvider) {
+	c.beaconRootProvider = p
+}
+
+// nextParentBeaconBlockRoot resolves the parentBeaconBlockRoot for the
+// payload built at height.
+func (c *EngineAPIExecutionClient) nextParentBeaconBlockRoot(height uint64) (common.Hash, error) {
+	if c.beaconRootProvider == nil {
+		return common.Hash{}, nil
+	}
+	return c.beaconRootProvider.ParentBeaconBlockRoot(height)
+}
+
+// InMemoryBeaconRootProvider is a BeaconRootProvider backed by an in-memory
+// map, keyed by the height of the payload the root belongs to.
+type InMemoryBeaconRootProvider struct {
+	mu    sync.Mutex
+	roots map[uint64]common.Hash
+}
+
+// NewInMemoryBeaconRootProvider creates an empty InMemoryBeaconRootProvider.
+func NewInMemoryBeaconRootProvider() *InMemoryBeaconRootProvider {
+	return &InMemoryBeaconRootProvider{roots: make(map[uint64]common.Hash)}
+}
+
+// Set records the parent beacon block root to use for the payload built at
+// height.
+func (p *InMemoryBeaconRootProvider) Set(height uint64, root common.Hash) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.roots[height] = root
+}
+
+// ParentBeaconBlockRoot implements BeaconRootProvider.
+func (p *InMemoryBeaconRootProvider) ParentBeaconBlockRoot(height uint64) (common.Hash, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.roots[height], nil
+}
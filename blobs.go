@@ -0,0 +1,30 @@
+package execution
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	rollkit_types "github.com/rollkit/rollkit/types"
+)
+
+// deriveVersionedHashes extracts the EIP-4844 blob versioned hashes carried
+// by the block's transactions, in transaction order, for the
+// expectedBlobVersionedHashes parameter of engine_newPayloadV3/V4. The engine
+// validates this array against the blob hashes it decodes from the payload's
+// own transactions, so an incorrect list surfaces as an INVALID payload
+// status rather than silently passing.
+func deriveVersionedHashes(txs []rollkit_types.Tx) ([]common.Hash, error) {
+	// Must stay non-nil: it marshals as a bare RPC param, and the engine
+	// rejects a null expectedBlobVersionedHashes post-Cancun even when there
+	// are no blob transactions in the block.
+	hashes := []common.Hash{}
+	for i, raw := range txs {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return nil, fmt.Errorf("failed to decode transaction %d: %w", i, err)
+		}
+		hashes = append(hashes, tx.BlobHashes()...)
+	}
+	return hashes, nil
+}
@@ -0,0 +1,91 @@
+package execution
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// depositRequestType is the EIP-7685 request type byte identifying a deposit
+// request, per EIP-6110.
+const depositRequestType = byte(0x00)
+
+// DepositRequest mirrors an EIP-6110 deposit request as returned in
+// ExecutionPayloadV4.depositRequests.
+type DepositRequest struct {
+	PublicKey             hexutil.Bytes  `json:"pubkey"`
+	WithdrawalCredentials hexutil.Bytes  `json:"withdrawalCredentials"`
+	Amount                hexutil.Uint64 `json:"amount"` // gwei
+	Signature             hexutil.Bytes  `json:"signature"`
+	Index                 hexutil.Uint64 `json:"index"`
+}
+
+// DepositsListener is notified of the deposit requests included in a block,
+// so the consensus layer can forward them to a beacon-style validator
+// registry.
+type DepositsListener interface {
+	OnDeposits(height uint64, deposits []*DepositRequest) error
+}
+
+// SetDepositsListener configures the callback invoked with the deposit
+// requests included in each block once Prague is active.
+func (c *EngineAPIExecutionClient) SetDepositsListener(l DepositsListener) {
+	c.depositsListener = l
+}
+
+// deriveRequestsRoot computes the EIP-7685 requests hash for the deposit
+// request type: sha256(sha256(requestType || rlp(deposits))), i.e. the outer
+// hash over the concatenation of each type's inner hash, per
+// types.CalcRequestsHash in go-ethereum.
+func deriveRequestsRoot(deposits []*DepositRequest) common.Hash {
+	encoded, err := rlp.EncodeToBytes(deposits)
+	if err != nil {
+		// deposits are always RLP-encodable; a failure here indicates a bug
+		// in the DepositRequest type, not bad input.
+		panic(fmt.Sprintf("failed to rlp-encode deposit requests: %v", err))
+	}
+
+	inner := sha256.Sum256(append([]byte{depositRequestType}, encoded...))
+	outer := sha256.Sum256(inner[:])
+	return common.Hash(outer)
+}
+
+// verifyRequestsRoot checks that the engine's reported requestsRoot matches
+// the root derived from the deposit requests it returned.
+func verifyRequestsRoot(deposits []*DepositRequest, requestsRoot common.Hash) error {
+	expected := deriveRequestsRoot(deposits)
+	if expected != requestsRoot {
+		return fmt.Errorf("requests root mismatch: expected %s, got %s", expected, requestsRoot)
+	}
+	return nil
+}
+
+// blockMetadata records per-height data needed to regenerate an identical
+// payload when replaying blocks, which isn't otherwise recoverable from the
+// rollkit_types.Hash/uint64 returned by ExecuteTxs.
+type blockMetadata struct {
+	mu                     sync.Mutex
+	parentBeaconBlockRoots map[uint64]common.Hash
+}
+
+func (m *blockMetadata) recordParentBeaconBlockRoot(height uint64, root common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.parentBeaconBlockRoots == nil {
+		m.parentBeaconBlockRoots = make(map[uint64]common.Hash)
+	}
+	m.parentBeaconBlockRoots[height] = root
+}
+
+// ParentBeaconBlockRoot returns the parentBeaconBlockRoot used to build the
+// payload at height, if one was recorded (i.e. Cancun was active).
+func (c *EngineAPIExecutionClient) ParentBeaconBlockRoot(height uint64) (common.Hash, bool) {
+	c.blockMeta.mu.Lock()
+	defer c.blockMeta.mu.Unlock()
+	root, ok := c.blockMeta.parentBeaconBlockRoots[height]
+	return root, ok
+}
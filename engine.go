@@ -0,0 +1,98 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrPayloadSyncing is returned when the engine reports SYNCING for a
+	// payload: the call should be retried once the client has caught up.
+	ErrPayloadSyncing = errors.New("payload syncing")
+	// ErrPayloadAccepted is returned when the engine reports ACCEPTED for a
+	// payload whose parent it hasn't validated yet (a side chain payload).
+	ErrPayloadAccepted = errors.New("payload accepted")
+)
+
+// forkchoiceUpdated calls the given forkchoiceUpdated method and decodes the
+// response into a typed ForkChoiceResponse.
+func (c *EngineAPIExecutionClient) forkchoiceUpdated(ctx context.Context, method string, state ForkchoiceStateV1, attrs *PayloadAttributes) (*ForkChoiceResponse, error) {
+	var resp ForkChoiceResponse
+	var err error
+	if attrs != nil {
+		err = c.engineClient.CallContext(ctx, &resp, method, state, attrs)
+	} else {
+		err = c.engineClient.CallContext(ctx, &resp, method, state, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %w", method, err)
+	}
+	return &resp, nil
+}
+
+// getPayload calls the given getPayload method and decodes the response,
+// unwrapping the ExecutionPayloadEnvelope that engine_getPayloadV2 and later
+// return (engine_getPayloadV1 returns the ExecutableData directly).
+func (c *EngineAPIExecutionClient) getPayload(ctx context.Context, method string, id PayloadID) (*ExecutableData, error) {
+	if method == "engine_getPayloadV1" {
+		var data ExecutableData
+		if err := c.engineClient.CallContext(ctx, &data, method, id); err != nil {
+			return nil, fmt.Errorf("%s failed: %w", method, err)
+		}
+		return &data, nil
+	}
+
+	var envelope ExecutionPayloadEnvelope
+	if err := c.engineClient.CallContext(ctx, &envelope, method, id); err != nil {
+		return nil, fmt.Errorf("%s failed: %w", method, err)
+	}
+	if envelope.ExecutionPayload == nil {
+		return nil, fmt.Errorf("%s returned no execution payload", method)
+	}
+	return envelope.ExecutionPayload, nil
+}
+
+// newPayload calls the given newPayload method, including the extra
+// versioned-hash and parent-beacon-block-root parameters required by V3 and
+// later, and decodes the response into a typed PayloadStatusV1.
+func (c *EngineAPIExecutionClient) newPayload(ctx context.Context, method string, payload *ExecutableData, versionedHashes []common.Hash, parentBeaconBlockRoot *common.Hash) (*PayloadStatusV1, error) {
+	params := []interface{}{payload}
+	if strings.HasSuffix(method, "V3") || strings.HasSuffix(method, "V4") {
+		params = append(params, versionedHashes, parentBeaconBlockRoot)
+	}
+
+	var status PayloadStatusV1
+	if err := c.engineClient.CallContext(ctx, &status, method, params...); err != nil {
+		return nil, fmt.Errorf("%s failed: %w", method, err)
+	}
+	return &status, nil
+}
+
+// checkPayloadStatus turns a PayloadStatusV1 into a Go error, preserving the
+// latest valid hash and validation error reported by the engine instead of
+// collapsing every non-VALID status into ErrInvalidPayloadStatus.
+func checkPayloadStatus(status PayloadStatusV1) error {
+	switch PayloadStatus(status.Status) {
+	case PayloadStatusValid:
+		return nil
+	case PayloadStatusSyncing:
+		return ErrPayloadSyncing
+	case PayloadStatusAccepted:
+		return ErrPayloadAccepted
+	case PayloadStatusInvalid:
+		err := ErrInvalidPayloadStatus
+		if status.LatestValidHash != nil {
+			err = fmt.Errorf("%w: latest valid hash %s", err, status.LatestValidHash.Hex())
+		}
+		if status.ValidationError != nil {
+			err = fmt.Errorf("%w: %s", err, *status.ValidationError)
+		}
+		return err
+	default:
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidPayloadStatus, status.Status)
+	}
+}
@@ -0,0 +1,149 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ForkConfig describes the activation timestamps of the forks that affect the
+// Engine API payload version. A zero timestamp means the fork is active from
+// genesis; a nil timestamp means the fork is not scheduled.
+type ForkConfig struct {
+	ShanghaiTime *uint64 // activates PayloadAttributesV2 / withdrawals
+	CancunTime   *uint64 // activates PayloadAttributesV3 / blob gas fields
+	PragueTime   *uint64 // activates ExecutionPayloadV4 / deposit requests
+}
+
+// IsShanghai reports whether Shanghai is active at the given timestamp.
+func (f *ForkConfig) IsShanghai(timestamp uint64) bool {
+	return f != nil && f.ShanghaiTime != nil && timestamp >= *f.ShanghaiTime
+}
+
+// IsCancun reports whether Cancun is active at the given timestamp.
+func (f *ForkConfig) IsCancun(timestamp uint64) bool {
+	return f != nil && f.CancunTime != nil && timestamp >= *f.CancunTime
+}
+
+// IsPrague reports whether Prague is active at the given timestamp.
+func (f *ForkConfig) IsPrague(timestamp uint64) bool {
+	return f != nil && f.PragueTime != nil && timestamp >= *f.PragueTime
+}
+
+// PayloadAttributes mirrors the union of go-ethereum's PayloadAttributesV1-V3.
+// ParentBeaconBlockRoot is omitted when nil; Withdrawals has no omitempty and
+// is sent as null when nil.
+type PayloadAttributes struct {
+	Timestamp             hexutil.Uint64      `json:"timestamp"`
+	PrevRandao            common.Hash         `json:"prevRandao"`
+	SuggestedFeeRecipient common.Address      `json:"suggestedFeeRecipient"`
+	Withdrawals           []*types.Withdrawal `json:"withdrawals"`
+	ParentBeaconBlockRoot *common.Hash        `json:"parentBeaconBlockRoot,omitempty"`
+}
+
+// ExecutableData mirrors the union of go-ethereum's ExecutableDataV1-V4.
+type ExecutableData struct {
+	ParentHash      common.Hash         `json:"parentHash"`
+	FeeRecipient    common.Address      `json:"feeRecipient"`
+	StateRoot       common.Hash         `json:"stateRoot"`
+	ReceiptsRoot    common.Hash         `json:"receiptsRoot"`
+	LogsBloom       hexutil.Bytes       `json:"logsBloom"`
+	Random          common.Hash         `json:"random"`
+	Number          hexutil.Uint64      `json:"blockNumber"`
+	GasLimit        hexutil.Uint64      `json:"gasLimit"`
+	GasUsed         hexutil.Uint64      `json:"gasUsed"`
+	Timestamp       hexutil.Uint64      `json:"timestamp"`
+	ExtraData       hexutil.Bytes       `json:"extraData"`
+	BaseFeePerGas   *hexutil.Big        `json:"baseFeePerGas"`
+	BlockHash       common.Hash         `json:"blockHash"`
+	Transactions    []hexutil.Bytes     `json:"transactions"`
+	Withdrawals     []*types.Withdrawal `json:"withdrawals"`
+	BlobGasUsed     *hexutil.Uint64     `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas   *hexutil.Uint64     `json:"excessBlobGas,omitempty"`
+	DepositRequests []*DepositRequest   `json:"depositRequests,omitempty"`
+	RequestsRoot    *common.Hash        `json:"requestsRoot,omitempty"`
+}
+
+// ForkchoiceStateV1 mirrors go-ethereum's beacon/engine.ForkchoiceStateV1.
+type ForkchoiceStateV1 struct {
+	HeadBlockHash      common.Hash `json:"headBlockHash"`
+	SafeBlockHash      common.Hash `json:"safeBlockHash"`
+	FinalizedBlockHash common.Hash `json:"finalizedBlockHash"`
+}
+
+// PayloadStatusV1 mirrors go-ethereum's beacon/engine.PayloadStatusV1.
+type PayloadStatusV1 struct {
+	Status          string       `json:"status"`
+	LatestValidHash *common.Hash `json:"latestValidHash"`
+	ValidationError *string      `json:"validationError"`
+}
+
+// ForkChoiceResponse mirrors go-ethereum's beacon/engine.ForkChoiceResponse.
+type ForkChoiceResponse struct {
+	PayloadStatus PayloadStatusV1 `json:"payloadStatus"`
+	PayloadID     *PayloadID      `json:"payloadId"`
+}
+
+// PayloadID is the 8-byte identifier the engine assigns to a payload build
+// process, returned by forkchoiceUpdated and consumed by getPayload.
+type PayloadID [8]byte
+
+// String returns the 0x-prefixed hex encoding of the payload ID.
+func (p PayloadID) String() string {
+	return hexutil.Encode(p[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p PayloadID) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *PayloadID) UnmarshalJSON(data []byte) error {
+	var hex string
+	if err := json.Unmarshal(data, &hex); err != nil {
+		return err
+	}
+	decoded, err := hexutil.Decode(hex)
+	if err != nil {
+		return fmt.Errorf("invalid payload id %q: %w", hex, err)
+	}
+	if len(decoded) != len(p) {
+		return fmt.Errorf("invalid payload id length: got %d, want %d", len(decoded), len(p))
+	}
+	copy(p[:], decoded)
+	return nil
+}
+
+// ExecutionPayloadEnvelope wraps the response of engine_getPayloadV2 and
+// later, which bundle the execution payload together with its fee value.
+type ExecutionPayloadEnvelope struct {
+	ExecutionPayload *ExecutableData `json:"executionPayload"`
+	BlockValue       *hexutil.Big    `json:"blockValue"`
+}
+
+// engineMethods bundles the forkchoiceUpdated/getPayload/newPayload RPC
+// method names for a given payload version.
+type engineMethods struct {
+	forkchoiceUpdated string
+	getPayload        string
+	newPayload        string
+}
+
+// methodsForTimestamp picks the Engine API method set to use for a block
+// produced at the given timestamp, based on the configured fork schedule.
+func (c *EngineAPIExecutionClient) methodsForTimestamp(timestamp uint64) engineMethods {
+	switch {
+	case c.forkConfig.IsPrague(timestamp):
+		return engineMethods{"engine_forkchoiceUpdatedV3", "engine_getPayloadV4", "engine_newPayloadV4"}
+	case c.forkConfig.IsCancun(timestamp):
+		return engineMethods{"engine_forkchoiceUpdatedV3", "engine_getPayloadV3", "engine_newPayloadV3"}
+	case c.forkConfig.IsShanghai(timestamp):
+		return engineMethods{"engine_forkchoiceUpdatedV2", "engine_getPayloadV2", "engine_newPayloadV2"}
+	default:
+		return engineMethods{"engine_forkchoiceUpdatedV1", "engine_getPayloadV1", "engine_newPayloadV1"}
+	}
+}
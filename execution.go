@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -19,9 +22,10 @@ import (
 type PayloadStatus string
 
 const (
-	PayloadStatusValid   PayloadStatus = "VALID"
-	PayloadStatusInvalid PayloadStatus = "INVALID"
-	PayloadStatusSyncing PayloadStatus = "SYNCING"
+	PayloadStatusValid    PayloadStatus = "VALID"
+	PayloadStatusInvalid  PayloadStatus = "INVALID"
+	PayloadStatusSyncing  PayloadStatus = "SYNCING"
+	PayloadStatusAccepted PayloadStatus = "ACCEPTED"
 )
 
 var (
@@ -39,10 +43,42 @@ type EngineAPIExecutionClient struct {
 	ethClient    *ethclient.Client
 	genesisHash  common.Hash
 	feeRecipient common.Address
+	forkConfig   *ForkConfig
+
+	withdrawalsProvider WithdrawalsProvider
+	beaconRootProvider  BeaconRootProvider
+	txPool              *txPoolTracker
+	depositsListener    DepositsListener
+	blockMeta           blockMetadata
+	randaoSource        RandaoSource
 }
 
-// NewEngineAPIExecutionClient creates a new instance of EngineAPIExecutionClient
-func NewEngineAPIExecutionClient(config *proxy_json_rpc.Config, ethURL, engineURL string, genesisHash common.Hash, feeRecipient common.Address) (*EngineAPIExecutionClient, error) {
+// StartTxPoolTracking begins tracking the execution client's mempool in the
+// background so GetTxs can return an ordered, bounded batch instead of
+// polling and re-ranking the entire pool on every call. It must be called
+// before GetTxs to take effect; ctx bounds the tracker's lifetime.
+func (c *EngineAPIExecutionClient) StartTxPoolTracking(ctx context.Context, cfg TxPoolConfig) error {
+	tracker := newTxPoolTracker(c.ethClient, cfg)
+	if err := tracker.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start tx pool tracker: %w", err)
+	}
+	c.txPool = tracker
+	return nil
+}
+
+// SetWithdrawalsProvider configures the source of withdrawals to include in
+// payloads once Shanghai is active. If unset, payloads carry an empty
+// withdrawals list.
+func (c *EngineAPIExecutionClient) SetWithdrawalsProvider(p WithdrawalsProvider) {
+	c.withdrawalsProvider = p
+}
+
+// NewEngineAPIExecutionClient creates a new instance of EngineAPIExecutionClient.
+// forkConfig may be nil, in which case the client always speaks the pre-Shanghai
+// (V1) Engine API. jwtSecret authenticates the Engine API connection and is
+// required by every production execution client (op-geth, erigon, reth); pass
+// nil only when talking to a permissive local devnet.
+func NewEngineAPIExecutionClient(config *proxy_json_rpc.Config, ethURL, engineURL string, genesisHash common.Hash, feeRecipient common.Address, forkConfig *ForkConfig, jwtSecret []byte) (*EngineAPIExecutionClient, error) {
 	client := proxy_json_rpc.NewClient()
 	client.SetConfig(config)
 
@@ -51,7 +87,7 @@ func NewEngineAPIExecutionClient(config *proxy_json_rpc.Config, ethURL, engineUR
 		return nil, err
 	}
 
-	engineClient, err := rpc.Dial(engineURL)
+	engineClient, err := dialEngineAPI(engineURL, jwtSecret)
 	if err != nil {
 		return nil, err
 	}
@@ -62,9 +98,35 @@ func NewEngineAPIExecutionClient(config *proxy_json_rpc.Config, ethURL, engineUR
 		ethClient:    ethClient,
 		genesisHash:  genesisHash,
 		feeRecipient: feeRecipient,
+		forkConfig:   forkConfig,
 	}, nil
 }
 
+// dialEngineAPI connects to the Engine API over HTTP or WebSocket, attaching
+// a JWT bearer token to every request when jwtSecret is non-empty.
+func dialEngineAPI(engineURL string, jwtSecret []byte) (*rpc.Client, error) {
+	if len(jwtSecret) == 0 {
+		return rpc.Dial(engineURL)
+	}
+
+	ctx := context.Background()
+	rt := &jwtRoundTripper{secret: jwtSecret, next: http.DefaultTransport}
+
+	if strings.HasPrefix(engineURL, "http://") || strings.HasPrefix(engineURL, "https://") {
+		// HTTP: the round tripper mints a fresh token on every request, so the
+		// ±60s iat window is always satisfied.
+		return rpc.DialOptions(ctx, engineURL, rpc.WithHTTPClient(&http.Client{Transport: rt}))
+	}
+
+	// WebSocket: the JWT is only checked at the handshake, so a single token
+	// minted at dial time is sufficient.
+	token, err := rt.sign()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign engine api jwt: %w", err)
+	}
+	return rpc.DialOptions(ctx, engineURL, rpc.WithHeader("Authorization", "Bearer "+token))
+}
+
 // Start starts the execution client
 func (c *EngineAPIExecutionClient) Start(url string) error {
 	return c.client.Start(url)
@@ -86,43 +148,63 @@ func (c *EngineAPIExecutionClient) Stop() {
 // InitChain initializes the blockchain with genesis information
 func (c *EngineAPIExecutionClient) InitChain(genesisTime time.Time, initialHeight uint64, chainID string) (rollkit_types.Hash, uint64, error) {
 	ctx := context.Background()
-	var forkchoiceResult map[string]interface{}
-	err := c.engineClient.CallContext(ctx, &forkchoiceResult, "engine_forkchoiceUpdatedV1",
-		map[string]interface{}{
-			"headBlockHash":      c.genesisHash,
-			"safeBlockHash":      c.genesisHash,
-			"finalizedBlockHash": c.genesisHash,
-		},
-		map[string]interface{}{
-			"timestamp":             genesisTime.Unix(),
-			"prevRandao":            common.Hash{},
-			"suggestedFeeRecipient": c.feeRecipient,
-		},
-	)
-	if err != nil {
-		return rollkit_types.Hash{}, 0, fmt.Errorf("engine_forkchoiceUpdatedV1 failed: %w", err)
+	methods := c.methodsForTimestamp(uint64(genesisTime.Unix()))
+
+	attrs := PayloadAttributes{
+		Timestamp: hexutil.Uint64(genesisTime.Unix()),
+		// Seed prevRandao from the genesis hash rather than the zero hash, so
+		// the very first payload doesn't start from a known, predictable value.
+		PrevRandao:            c.genesisHash,
+		SuggestedFeeRecipient: c.feeRecipient,
+	}
+	if c.forkConfig.IsShanghai(uint64(genesisTime.Unix())) {
+		attrs.Withdrawals = []*types.Withdrawal{}
+	}
+	if c.forkConfig.IsCancun(uint64(genesisTime.Unix())) {
+		root, err := c.nextParentBeaconBlockRoot(initialHeight)
+		if err != nil {
+			return rollkit_types.Hash{}, 0, fmt.Errorf("failed to get parent beacon block root: %w", err)
+		}
+		attrs.ParentBeaconBlockRoot = &root
+		c.blockMeta.recordParentBeaconBlockRoot(initialHeight, root)
 	}
 
-	payloadID, ok := forkchoiceResult["payloadId"].(string)
-	if !ok {
+	state := ForkchoiceStateV1{
+		HeadBlockHash:      c.genesisHash,
+		SafeBlockHash:      c.genesisHash,
+		FinalizedBlockHash: c.genesisHash,
+	}
+	fcuResult, err := c.forkchoiceUpdated(ctx, methods.forkchoiceUpdated, state, &attrs)
+	if err != nil {
+		return rollkit_types.Hash{}, 0, err
+	}
+	if err := checkPayloadStatus(fcuResult.PayloadStatus); err != nil {
+		return rollkit_types.Hash{}, 0, err
+	}
+	if fcuResult.PayloadID == nil {
 		return rollkit_types.Hash{}, 0, ErrNilPayloadStatus
 	}
 
-	var payload map[string]interface{}
-	err = c.engineClient.CallContext(ctx, &payload, "engine_getPayloadV1", payloadID)
+	payload, err := c.getPayload(ctx, methods.getPayload, *fcuResult.PayloadID)
 	if err != nil {
-		return rollkit_types.Hash{}, 0, fmt.Errorf("engine_getPayloadV1 failed: %w", err)
+		return rollkit_types.Hash{}, 0, err
 	}
 
-	stateRoot := common.HexToHash(payload["stateRoot"].(string))
-	gasLimit := uint64(payload["gasLimit"].(float64))
 	var rollkitStateRoot rollkit_types.Hash
-	copy(rollkitStateRoot[:], stateRoot[:])
-	return rollkitStateRoot, gasLimit, nil
+	copy(rollkitStateRoot[:], payload.StateRoot[:])
+	return rollkitStateRoot, uint64(payload.GasLimit), nil
 }
 
-// GetTxs retrieves transactions from the transaction pool
+// GetTxs retrieves transactions from the transaction pool. When
+// StartTxPoolTracking has been called, it returns a deterministic,
+// gas/nonce-ordered batch from the tracker, which keeps those transactions
+// until ExecuteTxs includes them; otherwise it falls back to
+// polling the entire pending+queued pool via txpool_content.
 func (c *EngineAPIExecutionClient) GetTxs() ([]rollkit_types.Tx, error) {
+	if c.txPool != nil {
+		return c.getTxsFromTracker()
+	}
+
 	ctx := context.Background()
 	var result struct {
 		Pending map[string]map[string]*types.Transaction `json:"pending"`
@@ -158,57 +240,108 @@ func (c *EngineAPIExecutionClient) GetTxs() ([]rollkit_types.Tx, error) {
 // ExecuteTxs executes the given transactions and returns the new state root and gas used
 func (c *EngineAPIExecutionClient) ExecuteTxs(txs []rollkit_types.Tx, height uint64, timestamp time.Time, prevStateRoot rollkit_types.Hash) (rollkit_types.Hash, uint64, error) {
 	ctx := context.Background()
-	ethTxs := make([][]byte, len(txs))
+	ethTxs := make([]hexutil.Bytes, len(txs))
 	for i, tx := range txs {
-		ethTxs[i] = tx
-	}
-
-	prevRandao := c.derivePrevRandao(height)
-	var forkchoiceResult map[string]interface{}
-	err := c.engineClient.CallContext(ctx, &forkchoiceResult, "engine_forkchoiceUpdatedV1",
-		map[string]interface{}{
-			"headBlockHash":      common.BytesToHash(prevStateRoot[:]),
-			"safeBlockHash":      common.BytesToHash(prevStateRoot[:]),
-			"finalizedBlockHash": common.BytesToHash(prevStateRoot[:]),
-		},
-		map[string]interface{}{
-			"timestamp":             timestamp.Unix(),
-			"prevRandao":            prevRandao,
-			"suggestedFeeRecipient": c.feeRecipient,
-		},
-	)
+		ethTxs[i] = hexutil.Bytes(tx)
+	}
+
+	ts := uint64(timestamp.Unix())
+	methods := c.methodsForTimestamp(ts)
+
+	prevRandao, err := c.nextPrevRandao(height, prevStateRoot)
 	if err != nil {
-		return rollkit_types.Hash{}, 0, fmt.Errorf("engine_forkchoiceUpdatedV1 failed: %w", err)
+		return rollkit_types.Hash{}, 0, fmt.Errorf("failed to derive prevRandao: %w", err)
+	}
+	attrs := PayloadAttributes{
+		Timestamp:             hexutil.Uint64(ts),
+		PrevRandao:            prevRandao,
+		SuggestedFeeRecipient: c.feeRecipient,
+	}
+	if c.forkConfig.IsShanghai(ts) {
+		attrs.Withdrawals = []*types.Withdrawal{}
+		if c.withdrawalsProvider != nil {
+			withdrawals, err := c.withdrawalsProvider.NextWithdrawals(height)
+			if err != nil {
+				return rollkit_types.Hash{}, 0, fmt.Errorf("failed to get withdrawals: %w", err)
+			}
+			attrs.Withdrawals = withdrawals
+		}
+	}
+	if c.forkConfig.IsCancun(ts) {
+		root, err := c.nextParentBeaconBlockRoot(height)
+		if err != nil {
+			return rollkit_types.Hash{}, 0, fmt.Errorf("failed to get parent beacon block root: %w", err)
+		}
+		attrs.ParentBeaconBlockRoot = &root
+		c.blockMeta.recordParentBeaconBlockRoot(height, root)
 	}
 
-	payloadID, ok := forkchoiceResult["payloadId"].(string)
-	if !ok {
+	state := ForkchoiceStateV1{
+		HeadBlockHash:      common.BytesToHash(prevStateRoot[:]),
+		SafeBlockHash:      common.BytesToHash(prevStateRoot[:]),
+		FinalizedBlockHash: common.BytesToHash(prevStateRoot[:]),
+	}
+	fcuResult, err := c.forkchoiceUpdated(ctx, methods.forkchoiceUpdated, state, &attrs)
+	if err != nil {
+		return rollkit_types.Hash{}, 0, err
+	}
+	if err := checkPayloadStatus(fcuResult.PayloadStatus); err != nil {
+		return rollkit_types.Hash{}, 0, err
+	}
+	if fcuResult.PayloadID == nil {
 		return rollkit_types.Hash{}, 0, ErrNilPayloadStatus
 	}
 
-	var payload map[string]interface{}
-	err = c.engineClient.CallContext(ctx, &payload, "engine_getPayloadV1", payloadID)
+	payload, err := c.getPayload(ctx, methods.getPayload, *fcuResult.PayloadID)
 	if err != nil {
-		return rollkit_types.Hash{}, 0, fmt.Errorf("engine_getPayloadV1 failed: %w", err)
+		return rollkit_types.Hash{}, 0, err
 	}
 
-	payload["transactions"] = ethTxs
-	var newPayloadResult map[string]interface{}
-	err = c.engineClient.CallContext(ctx, &newPayloadResult, "engine_newPayloadV1", payload)
+	if c.forkConfig.IsShanghai(ts) {
+		if err := verifyWithdrawalsRoot(attrs.Withdrawals, deriveWithdrawalsRoot(payload)); err != nil {
+			return rollkit_types.Hash{}, 0, err
+		}
+	}
+
+	if c.forkConfig.IsPrague(ts) {
+		if payload.RequestsRoot != nil {
+			if err := verifyRequestsRoot(payload.DepositRequests, *payload.RequestsRoot); err != nil {
+				return rollkit_types.Hash{}, 0, err
+			}
+		}
+		if c.depositsListener != nil {
+			if err := c.depositsListener.OnDeposits(height, payload.DepositRequests); err != nil {
+				return rollkit_types.Hash{}, 0, fmt.Errorf("deposits listener failed: %w", err)
+			}
+		}
+	}
+
+	payload.Transactions = ethTxs
+	var parentBeaconBlockRoot *common.Hash
+	if c.forkConfig.IsCancun(ts) {
+		parentBeaconBlockRoot = attrs.ParentBeaconBlockRoot
+	}
+
+	versionedHashes, err := deriveVersionedHashes(txs)
 	if err != nil {
-		return rollkit_types.Hash{}, 0, fmt.Errorf("engine_newPayloadV1 failed: %w", err)
+		return rollkit_types.Hash{}, 0, fmt.Errorf("failed to derive blob versioned hashes: %w", err)
 	}
 
-	status, ok := newPayloadResult["status"].(string)
-	if !ok || PayloadStatus(status) != PayloadStatusValid {
-		return rollkit_types.Hash{}, 0, ErrInvalidPayloadStatus
+	newPayloadStatus, err := c.newPayload(ctx, methods.newPayload, payload, versionedHashes, parentBeaconBlockRoot)
+	if err != nil {
+		return rollkit_types.Hash{}, 0, err
+	}
+	if err := checkPayloadStatus(*newPayloadStatus); err != nil {
+		return rollkit_types.Hash{}, 0, err
+	}
+
+	if c.txPool != nil {
+		c.txPool.remove(txHashes(txs))
 	}
 
-	newStateRoot := common.HexToHash(payload["stateRoot"].(string))
-	gasUsed := uint64(payload["gasUsed"].(float64))
 	var rollkitNewStateRoot rollkit_types.Hash
-	copy(rollkitNewStateRoot[:], newStateRoot[:])
-	return rollkitNewStateRoot, gasUsed, nil
+	copy(rollkitNewStateRoot[:], payload.StateRoot[:])
+	return rollkitNewStateRoot, uint64(payload.GasUsed), nil
 }
 
 // SetFinal marks a block at the given height as final
@@ -219,27 +352,19 @@ func (c *EngineAPIExecutionClient) SetFinal(height uint64) error {
 		return fmt.Errorf("failed to get block at height %d: %w", height, err)
 	}
 
-	var result map[string]interface{}
-	err = c.engineClient.CallContext(ctx, &result, "engine_forkchoiceUpdatedV1",
-		map[string]interface{}{
-			"headBlockHash":      block.Hash(),
-			"safeBlockHash":      block.Hash(),
-			"finalizedBlockHash": block.Hash(),
-		},
-		nil, // No payload attributes for finalization
-	)
-	if err != nil {
-		return fmt.Errorf("engine_forkchoiceUpdatedV1 failed for finalization: %w", err)
+	state := ForkchoiceStateV1{
+		HeadBlockHash:      block.Hash(),
+		SafeBlockHash:      block.Hash(),
+		FinalizedBlockHash: block.Hash(),
 	}
-
-	payloadStatus, ok := result["payloadStatus"].(map[string]interface{})
-	if !ok {
-		return ErrNilPayloadStatus
+	methods := c.methodsForTimestamp(block.Time())
+	result, err := c.forkchoiceUpdated(ctx, methods.forkchoiceUpdated, state, nil)
+	if err != nil {
+		return fmt.Errorf("finalization failed: %w", err)
 	}
 
-	status, ok := payloadStatus["status"].(string)
-	if !ok || PayloadStatus(status) != PayloadStatusValid {
-		return ErrInvalidPayloadStatus
+	if err := checkPayloadStatus(result.PayloadStatus); err != nil {
+		return err
 	}
 
 	return nil
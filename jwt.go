@@ -0,0 +1,59 @@
+package execution
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtRoundTripper mints a fresh HS256-signed JWT on every outbound request
+// and attaches it as an `Authorization: Bearer` header, as required by the
+// Engine API authentication spec (the `iat` claim must be within ±60s of the
+// server's clock).
+type jwtRoundTripper struct {
+	secret []byte
+	next   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *jwtRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := rt.sign()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign engine api jwt: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return rt.next.RoundTrip(req)
+}
+
+func (rt *jwtRoundTripper) sign() (string, error) {
+	claims := jwt.RegisteredClaims{
+		IssuedAt: jwt.NewNumericDate(time.Now()),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(rt.secret)
+}
+
+// LoadJWTSecret reads a hex-encoded JWT secret from path, as produced by
+// geth's `--authrpc.jwtsecret`. Surrounding whitespace and an optional "0x"
+// prefix are stripped.
+func LoadJWTSecret(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt secret file: %w", err)
+	}
+
+	hexSecret := strings.TrimSpace(string(raw))
+	hexSecret = strings.TrimPrefix(hexSecret, "0x")
+
+	secret, err := hex.DecodeString(hexSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode jwt secret: %w", err)
+	}
+	return secret, nil
+}
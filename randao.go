@@ -0,0 +1,175 @@
+package execution
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	rollkit_types "github.com/rollkit/rollkit/types"
+)
+
+// RandaoSource supplies the prevRandao value for the block being built at
+// height, given the previous block's state root. Implementations back
+// PREVRANDAO/DIFFICULTY opcode usage in rollup contracts and must not be
+// predictable ahead of time.
+type RandaoSource interface {
+	Next(height uint64, prevStateRoot rollkit_types.Hash) (common.Hash, error)
+}
+
+// SetRandaoSource configures where ExecuteTxs draws prevRandao from. If
+// unset, the client falls back to derivePrevRandao, which is trivially
+// predictable and should only be used for local development.
+func (c *EngineAPIExecutionClient) SetRandaoSource(s RandaoSource) {
+	c.randaoSource = s
+}
+
+// nextPrevRandao resolves the prevRandao value for the block at height.
+func (c *EngineAPIExecutionClient) nextPrevRandao(height uint64, prevStateRoot rollkit_types.Hash) (common.Hash, error) {
+	if c.randaoSource == nil {
+		return c.derivePrevRandao(height), nil
+	}
+	return c.randaoSource.Next(height, prevStateRoot)
+}
+
+// VRFRandaoSource derives prevRandao as H(sign(prevStateRoot)) under an
+// ECDSA key, the same construction a sequencer can prove was honestly
+// computed: anyone with the signer's public key can verify the signature
+// over prevStateRoot without being able to predict it beforehand.
+type VRFRandaoSource struct {
+	key *ecdsa.PrivateKey
+
+	mu     sync.Mutex
+	proofs map[uint64][]byte // height -> signature, kept alongside the block for verification
+}
+
+// NewVRFRandaoSource creates a VRFRandaoSource signing with key.
+func NewVRFRandaoSource(key *ecdsa.PrivateKey) *VRFRandaoSource {
+	return &VRFRandaoSource{key: key, proofs: make(map[uint64][]byte)}
+}
+
+// Next implements RandaoSource.
+func (s *VRFRandaoSource) Next(height uint64, prevStateRoot rollkit_types.Hash) (common.Hash, error) {
+	sig, err := crypto.Sign(prevStateRoot[:], s.key)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to sign randao input: %w", err)
+	}
+
+	s.mu.Lock()
+	s.proofs[height] = sig
+	s.mu.Unlock()
+
+	return crypto.Keccak256Hash(sig), nil
+}
+
+// Proof returns the signature produced for height, if any, so it can be
+// stored alongside the block and later verified against the signer's
+// public key.
+func (s *VRFRandaoSource) Proof(height uint64) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	proof, ok := s.proofs[height]
+	return proof, ok
+}
+
+// DrandRandaoSource draws prevRandao from the latest published round of a
+// drand randomness beacon, ignoring prevStateRoot entirely: randomness comes
+// from the beacon network, not local state.
+type DrandRandaoSource struct {
+	endpoint   string // e.g. "https://api.drand.sh/public/latest"
+	httpClient *http.Client
+}
+
+// NewDrandRandaoSource creates a DrandRandaoSource fetching rounds from endpoint.
+func NewDrandRandaoSource(endpoint string) *DrandRandaoSource {
+	return &DrandRandaoSource{endpoint: endpoint, httpClient: http.DefaultClient}
+}
+
+// Next implements RandaoSource.
+func (s *DrandRandaoSource) Next(height uint64, prevStateRoot rollkit_types.Hash) (common.Hash, error) {
+	resp, err := s.httpClient.Get(s.endpoint)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to fetch drand round: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var round struct {
+		Randomness string `json:"randomness"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&round); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to decode drand round: %w", err)
+	}
+
+	return common.HexToHash(round.Randomness), nil
+}
+
+// CommitRevealRandaoSource implements a simple commit-reveal scheme over a
+// fixed sequencer set: every participant commits to H(secret) ahead of time,
+// and prevRandao for height is the XOR of the secrets revealed for it once
+// every participant has revealed.
+type CommitRevealRandaoSource struct {
+	mu           sync.Mutex
+	commitments  map[uint64]map[common.Address]common.Hash // height -> participant -> H(secret)
+	reveals      map[uint64]map[common.Address]common.Hash // height -> participant -> secret
+	participants []common.Address
+}
+
+// NewCommitRevealRandaoSource creates a CommitRevealRandaoSource for the
+// given sequencer set.
+func NewCommitRevealRandaoSource(participants []common.Address) *CommitRevealRandaoSource {
+	return &CommitRevealRandaoSource{
+		commitments:  make(map[uint64]map[common.Address]common.Hash),
+		reveals:      make(map[uint64]map[common.Address]common.Hash),
+		participants: participants,
+	}
+}
+
+// Commit records participant's commitment H(secret) for height.
+func (s *CommitRevealRandaoSource) Commit(height uint64, participant common.Address, commitment common.Hash) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.commitments[height] == nil {
+		s.commitments[height] = make(map[common.Address]common.Hash)
+	}
+	s.commitments[height][participant] = commitment
+}
+
+// Reveal records participant's secret for height, verifying it against the
+// commitment recorded earlier.
+func (s *CommitRevealRandaoSource) Reveal(height uint64, participant common.Address, secret common.Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commitment, ok := s.commitments[height][participant]
+	if !ok || commitment != crypto.Keccak256Hash(secret[:]) {
+		return fmt.Errorf("reveal does not match commitment for participant %s at height %d", participant, height)
+	}
+	if s.reveals[height] == nil {
+		s.reveals[height] = make(map[common.Address]common.Hash)
+	}
+	s.reveals[height][participant] = secret
+	return nil
+}
+
+// Next implements RandaoSource. It returns an error if not every participant
+// has revealed for height yet.
+func (s *CommitRevealRandaoSource) Next(height uint64, prevStateRoot rollkit_types.Hash) (common.Hash, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revealed := s.reveals[height]
+	var randao common.Hash
+	for _, participant := range s.participants {
+		secret, ok := revealed[participant]
+		if !ok {
+			return common.Hash{}, fmt.Errorf("missing reveal from participant %s at height %d", participant, height)
+		}
+		for i := range randao {
+			randao[i] ^= secret[i]
+		}
+	}
+	return randao, nil
+}
@@ -0,0 +1,270 @@
+package execution
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	rollkit_types "github.com/rollkit/rollkit/types"
+)
+
+// TxPoolConfig bounds how much of the tracked mempool GetTxs hands back for a
+// single block.
+type TxPoolConfig struct {
+	MaxTxsPerBlock uint64
+	MaxGasPerBlock uint64
+	// PollInterval is how often to re-poll txpool_content when the node only
+	// exposes HTTP and newPendingTransactions can't be subscribed to.
+	PollInterval time.Duration
+}
+
+// txPoolTracker maintains an ordered view of the mempool by subscribing to
+// newPendingTransactions (falling back to periodic txpool_content polling),
+// so GetTxs can return a deterministic, gas/nonce-ordered slice instead of
+// re-downloading and re-ranking the whole pool on every call.
+type txPoolTracker struct {
+	ethClient *ethclient.Client
+	cfg       TxPoolConfig
+
+	mu       sync.Mutex
+	bySender map[common.Address][]*types.Transaction // sorted ascending by nonce
+	byHash   map[common.Hash]*types.Transaction
+}
+
+func newTxPoolTracker(client *ethclient.Client, cfg TxPoolConfig) *txPoolTracker {
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	return &txPoolTracker{
+		ethClient: client,
+		cfg:       cfg,
+		bySender:  make(map[common.Address][]*types.Transaction),
+		byHash:    make(map[common.Hash]*types.Transaction),
+	}
+}
+
+// Start begins tracking the mempool. It subscribes to newPendingTransactions
+// over websocket when available, otherwise falls back to periodically
+// polling txpool_content. Tracking continues in a goroutine until ctx is
+// canceled.
+func (t *txPoolTracker) Start(ctx context.Context) error {
+	hashes := make(chan common.Hash, 256)
+	sub, err := t.ethClient.Client().EthSubscribe(ctx, hashes, "newPendingTransactions")
+	if err != nil {
+		go t.pollLoop(ctx)
+		return nil
+	}
+
+	go t.consumeSubscription(ctx, sub, hashes)
+	return nil
+}
+
+func (t *txPoolTracker) consumeSubscription(ctx context.Context, sub *rpc.ClientSubscription, hashes chan common.Hash) {
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-sub.Err():
+			if err != nil {
+				go t.pollLoop(ctx) // degrade to polling rather than losing tracking entirely
+			}
+			return
+		case hash := <-hashes:
+			tx, isPending, err := t.ethClient.TransactionByHash(ctx, hash)
+			if err != nil || !isPending {
+				continue
+			}
+			t.add(tx)
+		}
+	}
+}
+
+func (t *txPoolTracker) pollLoop(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.pollOnce(ctx)
+		}
+	}
+}
+
+func (t *txPoolTracker) pollOnce(ctx context.Context) {
+	var result struct {
+		Pending map[string]map[string]*types.Transaction `json:"pending"`
+	}
+	if err := t.ethClient.Client().CallContext(ctx, &result, "txpool_content"); err != nil {
+		return
+	}
+	for _, accountTxs := range result.Pending {
+		for _, tx := range accountTxs {
+			t.add(tx)
+		}
+	}
+}
+
+// add inserts tx into the tracker, keeping each sender's transactions sorted
+// by ascending nonce.
+func (t *txPoolTracker) add(tx *types.Transaction) {
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.byHash[tx.Hash()]; exists {
+		return
+	}
+	t.byHash[tx.Hash()] = tx
+
+	txs := t.bySender[sender]
+	idx := sort.Search(len(txs), func(i int) bool { return txs[i].Nonce() >= tx.Nonce() })
+	txs = append(txs, nil)
+	copy(txs[idx+1:], txs[idx:])
+	txs[idx] = tx
+	t.bySender[sender] = txs
+}
+
+// remove drops the given hashes from the tracker, called once their
+// transactions have been successfully included in a block.
+func (t *txPoolTracker) remove(hashes []common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, hash := range hashes {
+		tx, ok := t.byHash[hash]
+		if !ok {
+			continue
+		}
+		delete(t.byHash, hash)
+
+		sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			continue
+		}
+		txs := t.bySender[sender]
+		for i, candidate := range txs {
+			if candidate.Hash() == hash {
+				t.bySender[sender] = append(txs[:i], txs[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// senderHead is one entry in the priority queue used by Pending: the next
+// eligible (lowest untaken nonce) transaction for a given sender.
+type senderHead struct {
+	sender common.Address
+	idx    int // position within bySender[sender] of the next candidate tx
+	tx     *types.Transaction
+}
+
+// headHeap orders senderHead entries by descending gas fee cap, mirroring
+// geth's TransactionsByPriceAndNonce so the highest-paying eligible tx is
+// always emitted next.
+type headHeap []*senderHead
+
+func (h headHeap) Len() int { return len(h) }
+func (h headHeap) Less(i, j int) bool {
+	// Senders are seeded into the heap by ranging over the bySender map,
+	// whose iteration order Go randomizes, so a fee-cap tie must be broken
+	// deterministically rather than falling through to heap-seeding order.
+	if cmp := h[i].tx.GasFeeCap().Cmp(h[j].tx.GasFeeCap()); cmp != 0 {
+		return cmp > 0
+	}
+	return bytes.Compare(h[i].sender[:], h[j].sender[:]) < 0
+}
+func (h headHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *headHeap) Push(x interface{}) { *h = append(*h, x.(*senderHead)) }
+func (h *headHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// Pending returns up to MaxTxsPerBlock transactions (and up to MaxGasPerBlock
+// gas) from the tracked pool, without removing them from the tracker. The
+// eligible transaction with the highest gas fee cap comes next, and each
+// sender's transactions appear in ascending nonce order; transactions of
+// different senders may be interleaved.
+func (t *txPoolTracker) Pending() []*types.Transaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := &headHeap{}
+	heap.Init(h)
+	for sender, txs := range t.bySender {
+		if len(txs) > 0 {
+			heap.Push(h, &senderHead{sender: sender, idx: 0, tx: txs[0]})
+		}
+	}
+
+	var (
+		result  []*types.Transaction
+		gasUsed uint64
+	)
+	for h.Len() > 0 {
+		if t.cfg.MaxTxsPerBlock > 0 && uint64(len(result)) >= t.cfg.MaxTxsPerBlock {
+			break
+		}
+		head := heap.Pop(h).(*senderHead)
+		if t.cfg.MaxGasPerBlock > 0 && gasUsed+head.tx.Gas() > t.cfg.MaxGasPerBlock {
+			// The sender's later nonces can't be included without this tx.
+			continue
+		}
+		result = append(result, head.tx)
+		gasUsed += head.tx.Gas()
+
+		if next := head.idx + 1; next < len(t.bySender[head.sender]) {
+			heap.Push(h, &senderHead{sender: head.sender, idx: next, tx: t.bySender[head.sender][next]})
+		}
+	}
+	return result
+}
+
+// txHashes decodes the raw rollkit transactions back into their go-ethereum
+// hashes, so the tracker can drop included transactions from its queue.
+func txHashes(txs []rollkit_types.Tx) []common.Hash {
+	hashes := make([]common.Hash, 0, len(txs))
+	for _, raw := range txs {
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			continue
+		}
+		hashes = append(hashes, tx.Hash())
+	}
+	return hashes
+}
+
+// getTxsFromTracker takes an ordered, bounded batch of pending transactions
+// from the tracked mempool, leaving them tracked, and encodes them for the execution.Execute
+// interface.
+func (c *EngineAPIExecutionClient) getTxsFromTracker() ([]rollkit_types.Tx, error) {
+	txs := c.txPool.Pending()
+	out := make([]rollkit_types.Tx, len(txs))
+	for i, tx := range txs {
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+		}
+		out[i] = rollkit_types.Tx(raw)
+	}
+	return out, nil
+}
@@ -0,0 +1,76 @@
+package execution
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// WithdrawalsProvider supplies the withdrawals to include in the next
+// payload once Shanghai is active. Implementations are expected to be
+// populated out-of-band, e.g. by a sequencer watching bridge withdrawal
+// events, and to be safe for concurrent use.
+type WithdrawalsProvider interface {
+	// NextWithdrawals returns the withdrawals to include in the payload built
+	// for the given height, removing them from the provider.
+	NextWithdrawals(height uint64) ([]*types.Withdrawal, error)
+}
+
+// InMemoryWithdrawalsProvider is a WithdrawalsProvider backed by an in-memory
+// queue. It assigns each withdrawal the next sequential index and drains the
+// entire queue on every call to NextWithdrawals.
+type InMemoryWithdrawalsProvider struct {
+	mu      sync.Mutex
+	nextIdx uint64
+	pending []*types.Withdrawal
+}
+
+// NewInMemoryWithdrawalsProvider creates an empty InMemoryWithdrawalsProvider.
+func NewInMemoryWithdrawalsProvider() *InMemoryWithdrawalsProvider {
+	return &InMemoryWithdrawalsProvider{}
+}
+
+// Enqueue appends a withdrawal for validatorIdx of amountGwei gwei to
+// address, assigning it the next sequential withdrawal index.
+func (p *InMemoryWithdrawalsProvider) Enqueue(validatorIdx uint64, address common.Address, amountGwei uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pending = append(p.pending, &types.Withdrawal{
+		Index:     p.nextIdx,
+		Validator: validatorIdx,
+		Address:   address,
+		Amount:    amountGwei,
+	})
+	p.nextIdx++
+}
+
+// NextWithdrawals implements WithdrawalsProvider.
+func (p *InMemoryWithdrawalsProvider) NextWithdrawals(height uint64) ([]*types.Withdrawal, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	withdrawals := p.pending
+	p.pending = nil
+	return withdrawals, nil
+}
+
+// deriveWithdrawalsRoot computes the withdrawals trie root the engine would
+// have embedded in the block header for the given payload.
+func deriveWithdrawalsRoot(payload *ExecutableData) common.Hash {
+	return types.DeriveSha(types.Withdrawals(payload.Withdrawals), trie.NewStackTrie(nil))
+}
+
+// verifyWithdrawalsRoot checks that the withdrawals root derived from the
+// payload the engine actually built matches the root of the withdrawals we
+// requested, guarding against the execution client silently dropping them.
+func verifyWithdrawalsRoot(requested []*types.Withdrawal, gotRoot common.Hash) error {
+	expected := types.DeriveSha(types.Withdrawals(requested), trie.NewStackTrie(nil))
+	if expected != gotRoot {
+		return fmt.Errorf("withdrawals root mismatch: expected %s, got %s", expected, gotRoot)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	rollkit_types "github.com/rollkit/rollkit/types"
+)
+
+// newMockEngine starts a minimal JSON-RPC server that plays back canned responses
+// for the Engine API methods exercised by ExecuteTxs, so withdrawals can be
+// round-tripped without a real execution client.
+func newMockEngine(t *testing.T, withdrawals []*types.Withdrawal) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "engine_forkchoiceUpdatedV2":
+			result = map[string]interface{}{
+				"payloadStatus": map[string]interface{}{"status": string(PayloadStatusValid)},
+				"payloadId":     "0x0000000000000001",
+			}
+		case "engine_getPayloadV2":
+			result = map[string]interface{}{
+				"executionPayload": map[string]interface{}{
+					"parentHash":    common.Hash{}.Hex(),
+					"feeRecipient":  common.Address{}.Hex(),
+					"stateRoot":     common.Hash{1}.Hex(),
+					"receiptsRoot":  common.Hash{}.Hex(),
+					"logsBloom":     "0x",
+					"random":        common.Hash{}.Hex(),
+					"blockNumber":   "0x1",
+					"gasLimit":      "0x1c9c380",
+					"gasUsed":       fmt.Sprintf("0x%x", 21000),
+					"timestamp":     "0x1",
+					"extraData":     "0x",
+					"baseFeePerGas": "0x1",
+					"blockHash":     common.Hash{2}.Hex(),
+					"transactions":  []string{},
+					"withdrawals":   withdrawals,
+				},
+				"blockValue": "0x0",
+			}
+		case "engine_newPayloadV2":
+			result = map[string]interface{}{"status": string(PayloadStatusValid)}
+		default:
+			http.Error(w, "unexpected method "+req.Method, http.StatusBadRequest)
+			return
+		}
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestExecuteTxs_WithdrawalsRoundTrip(t *testing.T) {
+	shanghai := uint64(0)
+	client := &EngineAPIExecutionClient{
+		feeRecipient: common.Address{},
+		forkConfig:   &ForkConfig{ShanghaiTime: &shanghai},
+	}
+
+	provider := NewInMemoryWithdrawalsProvider()
+	provider.Enqueue(1, common.HexToAddress("0xabc"), 1000)
+	client.SetWithdrawalsProvider(provider)
+
+	withdrawals := []*types.Withdrawal{{Index: 0, Validator: 1, Address: common.HexToAddress("0xabc"), Amount: 1000}}
+
+	server := newMockEngine(t, withdrawals)
+	defer server.Close()
+
+	engineClient, err := rpc.Dial(server.URL)
+	if err != nil {
+		t.Fatalf("failed to dial mock engine: %v", err)
+	}
+	defer engineClient.Close()
+	client.engineClient = engineClient
+
+	stateRoot, gasUsed, err := client.ExecuteTxs(nil, 1, time.Unix(1, 0), rollkit_types.Hash{})
+	if err != nil {
+		t.Fatalf("ExecuteTxs failed: %v", err)
+	}
+	if gasUsed != 21000 {
+		t.Errorf("expected gasUsed 21000, got %d", gasUsed)
+	}
+	if stateRoot != (rollkit_types.Hash{1}) {
+		t.Errorf("unexpected state root: %x", stateRoot)
+	}
+}
+
+func TestExecuteTxs_WithdrawalsRootMismatch(t *testing.T) {
+	shanghai := uint64(0)
+	client := &EngineAPIExecutionClient{
+		feeRecipient: common.Address{},
+		forkConfig:   &ForkConfig{ShanghaiTime: &shanghai},
+	}
+	client.SetWithdrawalsProvider(NewInMemoryWithdrawalsProvider())
+
+	// The engine returns a withdrawal the local queue never requested.
+	unexpected := []*types.Withdrawal{{Index: 0, Validator: 2, Address: common.HexToAddress("0xdead"), Amount: 1}}
+	server := newMockEngine(t, unexpected)
+	defer server.Close()
+
+	engineClient, err := rpc.Dial(server.URL)
+	if err != nil {
+		t.Fatalf("failed to dial mock engine: %v", err)
+	}
+	defer engineClient.Close()
+	client.engineClient = engineClient
+
+	if _, _, err := client.ExecuteTxs(nil, 1, time.Unix(1, 0), rollkit_types.Hash{}); err == nil {
+		t.Fatal("expected withdrawals root mismatch error, got nil")
+	}
+}